@@ -0,0 +1,60 @@
+// Package auth implements the HTTP authentication schemes httppal can attach
+// to outgoing requests.
+package auth
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Type identifies an authentication scheme.
+type Type string
+
+const (
+	Basic  Type = "basic"
+	Digest Type = "digest"
+	Bearer Type = "bearer"
+	NTLM   Type = "ntlm"
+)
+
+// Params holds the credentials needed to build a Provider for a given Type.
+// Not every field is used by every scheme: Basic and Digest use
+// Username/Password, Bearer uses Token, NTLM stubs both.
+type Params struct {
+	Type     Type
+	Username string
+	Password string
+	Token    string
+}
+
+// Provider attaches credentials to outgoing requests. Schemes that can
+// compute their header without seeing the server (Basic, Bearer) do so in
+// Prepare. Schemes that require a server challenge first (Digest, NTLM)
+// leave Prepare a no-op and respond to the challenge in the second round
+// trip via Challenge.
+type Provider interface {
+	// Prepare sets any Authorization header that can be computed up front.
+	Prepare(req *http.Request)
+
+	// Challenge computes the Authorization header value for a request that
+	// came back 401 with the given WWW-Authenticate header. ok is false if
+	// this provider doesn't respond to challenges (Basic, Bearer), in which
+	// case the 401 should just be returned to the caller as-is.
+	Challenge(method, uri, wwwAuthenticate string) (value string, ok bool, err error)
+}
+
+// NewProvider returns the Provider for params.Type.
+func NewProvider(params Params) (Provider, error) {
+	switch params.Type {
+	case Basic:
+		return &basicProvider{username: params.Username, password: params.Password}, nil
+	case Digest:
+		return &digestProvider{username: params.Username, password: params.Password}, nil
+	case Bearer:
+		return &bearerProvider{token: params.Token}, nil
+	case NTLM:
+		return &ntlmProvider{}, nil
+	default:
+		return nil, fmt.Errorf("auth: unsupported auth type %q", params.Type)
+	}
+}
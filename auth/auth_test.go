@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBasicProviderPrepare(t *testing.T) {
+	p, err := NewProvider(Params{Type: Basic, Username: "alice", Password: "wonder"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	p.Prepare(req)
+
+	got := req.Header.Get("Authorization")
+	if !strings.HasPrefix(got, "Basic ") {
+		t.Fatalf("Authorization = %q, want Basic prefix", got)
+	}
+}
+
+func TestBearerProviderPrepare(t *testing.T) {
+	p, err := NewProvider(Params{Type: Bearer, Token: "abc123"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	p.Prepare(req)
+
+	if got, want := req.Header.Get("Authorization"), "Bearer abc123"; got != want {
+		t.Fatalf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestNTLMProviderIsAStub(t *testing.T) {
+	p, err := NewProvider(Params{Type: NTLM})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if _, _, err := p.Challenge(http.MethodGet, "/", `NTLM`); err == nil {
+		t.Fatal("Challenge() error = nil, want unimplemented error")
+	}
+}
+
+func TestNewProviderUnknownType(t *testing.T) {
+	if _, err := NewProvider(Params{Type: "hmac"}); err == nil {
+		t.Fatal("NewProvider() error = nil, want error for unknown type")
+	}
+}
+
+func TestParseDigestChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+		want    digestChallenge
+	}{
+		{
+			name:   "realm nonce qop opaque",
+			header: `Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="abc123", opaque="xyz789"`,
+			want:   digestChallenge{Realm: "testrealm@host.com", Nonce: "abc123", Qop: "auth", Opaque: "xyz789"},
+		},
+		{
+			name:   "no qop",
+			header: `Digest realm="realm", nonce="n1"`,
+			want:   digestChallenge{Realm: "realm", Nonce: "n1"},
+		},
+		{
+			name:    "not digest",
+			header:  `Basic realm="realm"`,
+			wantErr: true,
+		},
+		{
+			name:    "missing nonce",
+			header:  `Digest realm="realm"`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			header:  `Digest realm="realm", nonce="n1", algorithm=MD5-sess`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDigestChallenge(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parseDigestChallenge() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDigestChallenge() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("parseDigestChallenge() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDigestProviderChallengeProducesValidHeader(t *testing.T) {
+	p, err := NewProvider(Params{Type: Digest, Username: "Mufasa", Password: "Circle Of Life"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	value, ok, err := p.Challenge(http.MethodGet, "/dir/index.html",
+		`Digest realm="testrealm@host.com", qop="auth", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`)
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Challenge() ok = false, want true")
+	}
+
+	for _, want := range []string{`username="Mufasa"`, `realm="testrealm@host.com"`, `uri="/dir/index.html"`, `qop=auth`, `nc=00000001`, `opaque="5ccc069c403ebaf9f0171e9517f40e41"`} {
+		if !strings.Contains(value, want) {
+			t.Errorf("Challenge() value = %q, want it to contain %q", value, want)
+		}
+	}
+}
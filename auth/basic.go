@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+type basicProvider struct {
+	username string
+	password string
+}
+
+func (p *basicProvider) Prepare(req *http.Request) {
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.username+":"+p.password)))
+}
+
+func (p *basicProvider) Challenge(method, uri, wwwAuthenticate string) (string, bool, error) {
+	return "", false, nil
+}
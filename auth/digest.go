@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type digestProvider struct {
+	username string
+	password string
+}
+
+func (p *digestProvider) Prepare(req *http.Request) {
+	// Digest can't compute a response until it sees the server's challenge.
+}
+
+func (p *digestProvider) Challenge(method, uri, wwwAuthenticate string) (string, bool, error) {
+	challenge, err := parseDigestChallenge(wwwAuthenticate)
+	if err != nil {
+		return "", true, err
+	}
+	value, err := buildDigestHeader(challenge, method, uri, p.username, p.password)
+	return value, true, err
+}
+
+// digestChallenge holds the fields httppal cares about from a
+// WWW-Authenticate: Digest ... challenge header.
+type digestChallenge struct {
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Qop       string
+	Algorithm string
+}
+
+// parseDigestChallenge parses the value of a WWW-Authenticate header that
+// starts with "Digest ". It returns an error if the header isn't a Digest
+// challenge or is missing the realm/nonce fields required to respond to it.
+func parseDigestChallenge(header string) (*digestChallenge, error) {
+	const prefix = "Digest "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, fmt.Errorf("not a Digest challenge: %q", header)
+	}
+
+	fields := parseAuthParams(header[len(prefix):])
+
+	c := &digestChallenge{
+		Realm:     fields["realm"],
+		Nonce:     fields["nonce"],
+		Opaque:    fields["opaque"],
+		Qop:       firstQop(fields["qop"]),
+		Algorithm: fields["algorithm"],
+	}
+	if c.Realm == "" || c.Nonce == "" {
+		return nil, fmt.Errorf("digest challenge missing realm or nonce: %q", header)
+	}
+	if c.Algorithm != "" && !strings.EqualFold(c.Algorithm, "MD5") {
+		return nil, fmt.Errorf("unsupported digest algorithm %q (only MD5 is implemented)", c.Algorithm)
+	}
+	return c, nil
+}
+
+// parseAuthParams splits a comma-separated list of key="value" (or bare
+// key=value) pairs, as used in WWW-Authenticate and Authorization headers.
+func parseAuthParams(s string) map[string]string {
+	params := map[string]string{}
+	for _, part := range splitAuthParams(s) {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		params[key] = val
+	}
+	return params
+}
+
+// splitAuthParams splits on commas that aren't inside a quoted string, since
+// values like qop="auth,auth-int" may themselves contain commas.
+func splitAuthParams(s string) []string {
+	var parts []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range s {
+		switch r {
+		case '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case ',':
+			if inQuotes {
+				cur.WriteRune(r)
+			} else {
+				parts = append(parts, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		parts = append(parts, cur.String())
+	}
+	return parts
+}
+
+// firstQop picks "auth" out of a (possibly quoted, comma-separated) qop-options
+// list if present, falling back to the first option offered.
+func firstQop(qop string) string {
+	if qop == "" {
+		return ""
+	}
+	options := strings.Split(qop, ",")
+	for _, o := range options {
+		if strings.TrimSpace(o) == "auth" {
+			return "auth"
+		}
+	}
+	return strings.TrimSpace(options[0])
+}
+
+// buildDigestHeader computes the Authorization header value for a digest
+// response to challenge, per RFC 2617, for the given method/uri/credentials.
+func buildDigestHeader(c *digestChallenge, method, uri, username, password string) (string, error) {
+	cnonce, err := randomHex(8)
+	if err != nil {
+		return "", fmt.Errorf("generating cnonce: %w", err)
+	}
+	const nc = "00000001"
+
+	ha1 := md5Hex(username + ":" + c.Realm + ":" + password)
+	ha2 := md5Hex(method + ":" + uri)
+
+	var response string
+	if c.Qop != "" {
+		response = md5Hex(strings.Join([]string{ha1, c.Nonce, nc, cnonce, c.Qop, ha2}, ":"))
+	} else {
+		response = md5Hex(ha1 + ":" + c.Nonce + ":" + ha2)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		username, c.Realm, c.Nonce, uri, response)
+	if c.Qop != "" {
+		fmt.Fprintf(&sb, `, qop=%s, nc=%s, cnonce="%s"`, c.Qop, nc, cnonce)
+	}
+	if c.Opaque != "" {
+		fmt.Fprintf(&sb, `, opaque="%s"`, c.Opaque)
+	}
+	return sb.String(), nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex returns n random bytes, hex-encoded.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,166 @@
+// Package httpclient implements httppal's request/response pipeline: it
+// builds requests from RequestParams, attaches auth, carries out the
+// single-retry dance digest-style schemes require on a 401, and re-issues
+// requests that fail transiently according to an optional retry.Policy.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gitynity/httppal/auth"
+	"github.com/gitynity/httppal/retry"
+)
+
+// RequestParams describes the request to send. Body is buffered up front
+// (rather than taken as an io.Reader) so it can be replayed if an auth
+// challenge or a retry forces another attempt.
+type RequestParams struct {
+	Method          string
+	URL             string
+	Headers         http.Header
+	Body            []byte
+	FollowRedirects bool
+	Auth            auth.Provider
+	Retry           *retry.Policy
+}
+
+// Response is the result of a request. Body is read out of the underlying
+// *http.Response.Body, which has already been closed by the time Do
+// returns.
+type Response struct {
+	*http.Response
+	Body []byte
+}
+
+// Client sends requests built from RequestParams.
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// New returns a Client with httppal's default timeout.
+func New() *Client {
+	return &Client{HTTPClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Do sends the request described by params, following the RFC 2617 retry
+// flow when params.Auth requires a server challenge, and re-issuing the
+// request per params.Retry on transient failures.
+func (c *Client) Do(ctx context.Context, params RequestParams) (*Response, error) {
+	httpClient := *c.HTTPClient
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !params.FollowRedirects {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+
+	policy := params.Retry
+	if policy == nil {
+		return c.attempt(ctx, &httpClient, params)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, &httpClient, params)
+
+		statusCode := 0
+		var retryAfter time.Duration
+		if resp != nil {
+			statusCode = resp.StatusCode
+			retryAfter, _ = retry.RetryAfter(resp.Response)
+		}
+		if !policy.ShouldRetry(attempt, statusCode, err) {
+			return resp, err
+		}
+
+		select {
+		case <-time.After(policy.Delay(attempt, retryAfter)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// attempt sends params once, including the single extra round trip a 401
+// digest/NTLM challenge requires.
+func (c *Client) attempt(ctx context.Context, httpClient *http.Client, params RequestParams) (*Response, error) {
+	req, err := newRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if params.Auth != nil {
+		params.Auth.Prepare(req)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readAndClose(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Auth == nil || resp.StatusCode != http.StatusUnauthorized {
+		return &Response{Response: resp, Body: body}, nil
+	}
+
+	challengeHeader := resp.Header.Get("WWW-Authenticate")
+	if challengeHeader == "" {
+		return &Response{Response: resp, Body: body}, nil
+	}
+
+	retryReq, err := newRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	value, ok, err := params.Auth.Challenge(retryReq.Method, retryReq.URL.RequestURI(), challengeHeader)
+	if !ok {
+		// This provider doesn't respond to challenges (Basic, Bearer); the
+		// 401 is the real answer.
+		return &Response{Response: resp, Body: body}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: responding to auth challenge: %w", err)
+	}
+	retryReq.Header.Set("Authorization", value)
+
+	retryResp, err := httpClient.Do(retryReq)
+	if err != nil {
+		return nil, err
+	}
+	retryBody, err := readAndClose(retryResp)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Response: retryResp, Body: retryBody}, nil
+}
+
+func newRequest(ctx context.Context, params RequestParams) (*http.Request, error) {
+	var body io.Reader
+	if params.Body != nil {
+		body = bytes.NewReader(params.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, params.Method, params.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: creating request: %w", err)
+	}
+	for k, v := range params.Headers {
+		req.Header.Set(k, v[0])
+	}
+	return req, nil
+}
+
+func readAndClose(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: reading response body: %w", err)
+	}
+	return body, nil
+}
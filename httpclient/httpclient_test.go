@@ -0,0 +1,147 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gitynity/httppal/auth"
+	"github.com/gitynity/httppal/retry"
+)
+
+func TestDoGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	resp, err := New().Do(context.Background(), RequestParams{Method: http.MethodGet, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if got, want := string(resp.Body), `{"ok":true}`; got != want {
+		t.Fatalf("Body = %q, want %q", got, want)
+	}
+	if got := resp.Header.Get("X-Test"); got != "yes" {
+		t.Fatalf("Header X-Test = %q, want yes", got)
+	}
+}
+
+func TestDoDigestChallengeAndRetry(t *testing.T) {
+	const username, password = "Mufasa", "Circle Of Life"
+	attempts := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Digest realm="testrealm", qop="auth", nonce="abc123"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("authenticated"))
+	}))
+	defer srv.Close()
+
+	provider, err := auth.NewProvider(auth.Params{Type: auth.Digest, Username: username, Password: password})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	resp, err := New().Do(context.Background(), RequestParams{Method: http.MethodGet, URL: srv.URL, Auth: provider})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if string(resp.Body) != "authenticated" {
+		t.Fatalf("Body = %q, want %q", resp.Body, "authenticated")
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2 (challenge then retry)", attempts)
+	}
+}
+
+func TestDoBuffersBodyForReplay(t *testing.T) {
+	var gotBodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBodies = append(gotBodies, string(b))
+		if len(gotBodies) == 1 {
+			w.Header().Set("WWW-Authenticate", `Digest realm="r", qop="auth", nonce="n"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	provider, _ := auth.NewProvider(auth.Params{Type: auth.Digest, Username: "u", Password: "p"})
+	_, err := New().Do(context.Background(), RequestParams{
+		Method: http.MethodPost,
+		URL:    srv.URL,
+		Body:   []byte(`{"hello":"world"}`),
+		Auth:   provider,
+	})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if len(gotBodies) != 2 || gotBodies[0] != gotBodies[1] {
+		t.Fatalf("request bodies = %v, want the same body replayed twice", gotBodies)
+	}
+}
+
+func TestDoRetriesOnStatusCode(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	policy := &retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, StatusCodes: retry.DefaultStatusCodes()}
+	resp, err := New().Do(context.Background(), RequestParams{Method: http.MethodGet, URL: srv.URL, Retry: policy})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestDoStopsRetryingAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := &retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, StatusCodes: retry.DefaultStatusCodes()}
+	resp, err := New().Do(context.Background(), RequestParams{Method: http.MethodGet, URL: srv.URL, Retry: policy})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("StatusCode = %d, want 503 (final failed attempt returned as-is)", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2 (MaxAttempts)", attempts)
+	}
+}
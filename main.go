@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/base64"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -12,30 +12,52 @@ import (
 	"os"
 	"strings"
 	"time"
-)
-
-type RequestParams struct {
-	Method          string
-	Headers         http.Header
-	Body            io.Reader
-	FollowRedirects bool
-	Auth            *AuthParams
-	QueryParams     url.Values
-}
 
-type AuthParams struct {
-	Username string
-	Password string
-}
+	"github.com/gitynity/httppal/auth"
+	"github.com/gitynity/httppal/httpclient"
+	"github.com/gitynity/httppal/render"
+	"github.com/gitynity/httppal/retry"
+	"github.com/gitynity/httppal/script"
+)
 
 func main() {
 	urlStr := flag.String("url", "", "The URL to make the request to")
 	method := flag.String("method", "GET", "The HTTP method to use")
 	reqBodyFile := flag.String("file", "", "The name of a file to use as the request body")
 	followRedirects := flag.Bool("follow", false, "Whether to follow redirects")
-	authStr := flag.String("auth", "", "The username and password for basic authentication in the format 'username:password'")
+	authStr := flag.String("auth", "", "Credentials for authentication: 'username:password', or a bearer token with -auth-type bearer")
+	authType := flag.String("auth-type", "basic", "The authentication scheme to use: 'basic', 'digest', or 'bearer'")
+	useNetrc := flag.Bool("netrc", true, "Look up credentials for the URL's host in $NETRC or ~/.netrc when -auth is not given")
+	output := flag.String("output", "auto", "How to format the response body: 'json', 'xml', 'raw', or 'auto' (Content-Type based)")
+	outputFile := flag.String("output-file", "", "Write the response body to this file instead of stdout")
+	retryAttempts := flag.Int("retry", 0, "Maximum number of retry attempts for transient failures (0 disables retrying)")
+	retryBackoff := flag.String("retry-backoff", "500ms", "Initial backoff delay between retries")
+	retryMaxBackoff := flag.String("retry-max-backoff", "10s", "Maximum backoff delay between retries")
+	retryOn := flag.String("retry-on", "", "Comma-separated status codes to retry on (default: 408,429,500,502,503,504)")
+	scriptFile := flag.String("script", "", "Run a multi-step .http request script instead of a single request")
+	envFile := flag.String("env", "", "Load script variables from a key=value file")
+	vars := script.Vars{}
+	flag.Var(varsFlag(vars), "var", "Seed a script variable as key=value (repeatable)")
 	flag.Parse()
 
+	retryPolicy, err := buildRetryPolicy(*retryAttempts, *retryBackoff, *retryMaxBackoff, *retryOn)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	if *scriptFile != "" {
+		if *outputFile != "" {
+			log.Fatalf("Error: -output-file is not supported with -script")
+		}
+		authResolver := func(rawURL string) (auth.Provider, string, error) {
+			return resolveStepAuth(*authStr, *authType, rawURL, *useNetrc)
+		}
+		if err := runScript(*scriptFile, *envFile, vars, *followRedirects, retryPolicy, *output, authResolver); err != nil {
+			log.Fatalf("Error: %s", err)
+		}
+		return
+	}
+
 	if *urlStr == "" {
 		fmt.Println("Usage: httpreq -url <url> [options]")
 		fmt.Println("Options:")
@@ -43,114 +65,253 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Parse the URL to ensure it's valid
-	_, err := url.Parse(*urlStr)
+	parsedURL, err := url.Parse(*urlStr)
 	if err != nil {
 		log.Fatalf("Error: Invalid URL '%s': %s", *urlStr, err)
 	}
 
-	// Read the request body from file if specified
-	var reqBody io.Reader
+	var reqBody []byte
 	if *reqBodyFile != "" {
-		file, err := os.Open(*reqBodyFile)
+		reqBody, err = ioutil.ReadFile(*reqBodyFile)
 		if err != nil {
 			log.Fatalf("Error: Could not read file '%s': %s", *reqBodyFile, err)
 		}
-		reqBody = file
 	}
 
-	// Parse the authentication credentials if specified
-	var auth *AuthParams
-	if *authStr != "" {
-		authParts := strings.SplitN(*authStr, ":", 2)
-		if len(authParts) != 2 {
-			log.Fatalf("Error: Invalid auth credentials '%s'", *authStr)
+	authParams, err := resolveAuth(*authStr, *authType, parsedURL, *useNetrc, urlStr)
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	var provider auth.Provider
+	if authParams != nil {
+		provider, err = auth.NewProvider(*authParams)
+		if err != nil {
+			log.Fatalf("Error: %s", err)
 		}
-		auth = &AuthParams{Username: authParts[0], Password: authParts[1]}
 	}
 
-	reqParams := &RequestParams{
+	headers, err := parseHeaderArgs(flag.Args())
+	if err != nil {
+		log.Fatalf("Error: %s", err)
+	}
+
+	resp, err := httpclient.New().Do(context.Background(), httpclient.RequestParams{
 		Method:          *method,
+		URL:             *urlStr,
+		Headers:         headers,
 		Body:            reqBody,
 		FollowRedirects: *followRedirects,
-		Auth:            auth,
+		Auth:            provider,
+		Retry:           retryPolicy,
+	})
+	if err != nil {
+		log.Fatalf("Error making request: %s", err)
+	}
+
+	// When the body goes to a file (written in one piece once the whole
+	// response has been read into memory), the status line and headers still
+	// need somewhere to go - send them to stderr so stdout/the file stay
+	// limited to the body itself.
+	statusOut := os.Stdout
+	if *outputFile != "" {
+		statusOut = os.Stderr
+	}
+	fmt.Fprintf(statusOut, "HTTP/%d.%d %s\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+	for k, v := range resp.Header {
+		fmt.Fprintf(statusOut, "%s: %s\n", k, v[0])
 	}
+	fmt.Fprintln(statusOut)
 
-	// Add headers from command-line options
-	for i := 0; i < flag.NArg(); i++ {
-		header := flag.Arg(i)
-		headerParts := strings.SplitN(header, ":", 2)
-		if len(headerParts) != 2 {
-			log.Fatalf("Error: Invalid header '%s'", header)
+	if *outputFile != "" {
+		if err := ioutil.WriteFile(*outputFile, resp.Body, 0644); err != nil {
+			log.Fatalf("Error writing response body to %q: %s", *outputFile, err)
 		}
-		reqParams.Headers.Set(strings.TrimSpace(headerParts[0]), strings.TrimSpace(headerParts[1]))
+		return
 	}
 
-	// Create the HTTP request object
-	req, err := http.NewRequest(reqParams.Method, *urlStr, reqParams.Body)
+	if err := renderResponse(os.Stdout, *output, resp); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// runScript executes a -script file as a sequence of requests, printing
+// each step's status, headers, and body as it completes.
+func runScript(path, envPath string, seedVars script.Vars, followRedirects bool, retryPolicy *retry.Policy, output string, authResolver script.AuthResolver) error {
+	f, err := os.Open(path)
 	if err != nil {
-		log.Fatalf("Error creating request object: %s", err)
+		return fmt.Errorf("reading script %q: %w", path, err)
 	}
+	defer f.Close()
 
-	// Add headers to the request object
-	for k, v := range reqParams.Headers {
-		req.Header.Set(k, v[0])
+	steps, err := script.Parse(f)
+	if err != nil {
+		return err
 	}
 
-	// Add authentication to the request object if specified
-	if reqParams.Auth != nil {
-		authHeader := "Basic " + base64.StdEncoding.EncodeToString([]byte(reqParams.Auth.Username+":"+reqParams.Auth.Password))
-		req.Header.Set("Authorization", authHeader)
+	vars := script.Vars{}
+	if envPath != "" {
+		envVars, err := script.LoadEnvFile(envPath)
+		if err != nil {
+			return err
+		}
+		for k, v := range envVars {
+			vars[k] = v
+		}
+	}
+	for k, v := range seedVars {
+		vars[k] = v
 	}
 
-	// Create a new HTTP client
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if !reqParams.FollowRedirects {
-				return http.ErrUseLastResponse
-			}
-			return nil
-		},
+	runner := script.NewRunner(httpclient.New(), vars, httpclient.RequestParams{
+		FollowRedirects: followRedirects,
+		Retry:           retryPolicy,
+	})
+	runner.AuthResolver = authResolver
+
+	return runner.Run(context.Background(), steps, func(result script.StepResult) {
+		fmt.Printf("### %s\n", result.Step.Name)
+		resp := result.Response
+		fmt.Printf("HTTP/%d.%d %s\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+		for k, v := range resp.Header {
+			fmt.Printf("%s: %s\n", k, v[0])
+		}
+		fmt.Println()
+		if err := renderResponse(os.Stdout, output, resp); err != nil {
+			fmt.Println(err)
+		}
+		fmt.Println()
+	})
+}
+
+// renderResponse picks a Renderer per the -output flag (falling back to
+// Content-Type-based auto-detection) and writes resp's body through it.
+func renderResponse(w io.Writer, output string, resp *httpclient.Response) error {
+	renderer, err := render.ForName(output)
+	if err != nil {
+		return err
+	}
+	if renderer == nil {
+		renderer = render.ForContentType(resp.Header.Get("Content-Type"))
 	}
+	return renderer.Render(w, resp.Response, resp.Body)
+}
+
+// varsFlag lets -var be repeated on the command line, each occurrence
+// adding one key=value pair to the underlying script.Vars.
+type varsFlag script.Vars
+
+func (v varsFlag) String() string { return "" }
 
-	// Make the HTTP request
-	resp, err := client.Do(req)
+func (v varsFlag) Set(s string) error {
+	key, value, err := script.ParseVarFlag(s)
 	if err != nil {
-		log.Fatalf("Error making request: %s", err)
+		return err
 	}
-	defer resp.Body.Close()
+	v[key] = value
+	return nil
+}
 
-	// Print the response status code
-	fmt.Printf("HTTP/%d.%d %s\n", resp.ProtoMajor, resp.ProtoMinor, resp.Status)
+// resolveAuth picks credentials from -auth, URL userinfo, or .netrc, in that
+// priority order. If userinfo supplies the credentials, *urlStr is rewritten
+// to strip them from the outgoing request line.
+func resolveAuth(authStr, authType string, parsedURL *url.URL, useNetrc bool, urlStr *string) (*auth.Params, error) {
+	if authStr != "" {
+		if authType == string(auth.Bearer) {
+			return &auth.Params{Type: auth.Bearer, Token: authStr}, nil
+		}
+		parts := strings.SplitN(authStr, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid auth credentials %q", authStr)
+		}
+		switch auth.Type(authType) {
+		case auth.Basic, auth.Digest:
+		default:
+			return nil, fmt.Errorf("unsupported -auth-type %q (expected 'basic', 'digest', or 'bearer')", authType)
+		}
+		return &auth.Params{Type: auth.Type(authType), Username: parts[0], Password: parts[1]}, nil
+	}
 
-	// Print the response headers
-	for k, v := range resp.Header {
-		fmt.Printf("%s: %s\n", k, v[0])
+	if u := userinfoAuth(parsedURL); u != nil {
+		parsedURL.User = nil
+		*urlStr = parsedURL.String()
+		return u, nil
 	}
-	fmt.Println()
 
-	// get response body
-	body, err := ioutil.ReadAll(resp.Body)
+	if useNetrc {
+		return lookupNetrc(parsedURL.Hostname())
+	}
+	return nil, nil
+}
+
+// resolveStepAuth adapts resolveAuth for script.AuthResolver: it parses
+// rawURL itself (each script step can target a different host, so -netrc
+// and URL-userinfo credentials are resolved per step rather than once up
+// front) and returns the provider to use along with rawURL stripped of any
+// userinfo credentials it consumed.
+func resolveStepAuth(authStr, authType, rawURL string, useNetrc bool) (auth.Provider, string, error) {
+	parsedURL, err := url.Parse(rawURL)
 	if err != nil {
-		log.Fatalf("Error reading response body: %s", err)
+		return nil, "", fmt.Errorf("invalid URL %q: %w", rawURL, err)
 	}
-	// Create a map to hold the JSON data
-	var data map[string]interface{}
 
-	// Unmarshal the JSON into the map
-	err = json.Unmarshal(body, &data)
+	authParams, err := resolveAuth(authStr, authType, parsedURL, useNetrc, &rawURL)
 	if err != nil {
-		fmt.Println("Error unmarshaling JSON:", err)
-		return
+		return nil, "", err
+	}
+	if authParams == nil {
+		return nil, rawURL, nil
 	}
 
-	// Pretty print the JSON
-	prettyJSON, err := json.MarshalIndent(data, "", "  ")
+	provider, err := auth.NewProvider(*authParams)
 	if err != nil {
-		fmt.Println("Error marshaling JSON:", err)
-		return
+		return nil, "", err
+	}
+	return provider, rawURL, nil
+}
+
+// buildRetryPolicy turns the -retry* flags into a retry.Policy, or returns
+// nil if retrying is disabled (the default).
+func buildRetryPolicy(attempts int, backoff, maxBackoff, retryOn string) (*retry.Policy, error) {
+	if attempts <= 0 {
+		return nil, nil
+	}
+
+	base, err := time.ParseDuration(backoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -retry-backoff %q: %w", backoff, err)
+	}
+	max, err := time.ParseDuration(maxBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -retry-max-backoff %q: %w", maxBackoff, err)
+	}
+
+	codes := retry.DefaultStatusCodes()
+	if retryOn != "" {
+		codes, err = retry.ParseStatusCodes(retryOn)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	fmt.Println(string(prettyJSON))
+	return &retry.Policy{
+		MaxAttempts: attempts + 1,
+		BaseDelay:   base,
+		MaxDelay:    max,
+		StatusCodes: codes,
+	}, nil
+}
+
+// parseHeaderArgs turns "Header: value" positional arguments into an
+// http.Header.
+func parseHeaderArgs(args []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid header %q", arg)
+		}
+		headers.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return headers, nil
 }
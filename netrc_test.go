@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeNetrc(t *testing.T, contents string) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".netrc")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("writing test .netrc: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening test .netrc: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseNetrc(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     []netrcEntry
+	}{
+		{
+			name:     "single machine",
+			contents: "machine example.com\nlogin alice\npassword wonder\n",
+			want:     []netrcEntry{{Machine: "example.com", Login: "alice", Password: "wonder"}},
+		},
+		{
+			name: "multiple machines and a default",
+			contents: `machine foo.com
+login foouser
+password foopass
+
+machine bar.com
+login baruser
+password barpass
+
+default
+login defuser
+password defpass
+`,
+			want: []netrcEntry{
+				{Machine: "foo.com", Login: "foouser", Password: "foopass"},
+				{Machine: "bar.com", Login: "baruser", Password: "barpass"},
+				{Machine: "default", Login: "defuser", Password: "defpass"},
+			},
+		},
+		{
+			name: "macdef block is skipped, following machine still parses",
+			contents: `machine foo.com
+login foouser
+password foopass
+
+macdef init
+put your shell macro
+commands here
+
+machine bar.com
+login baruser
+password barpass
+`,
+			want: []netrcEntry{
+				{Machine: "foo.com", Login: "foouser", Password: "foopass"},
+				{Machine: "bar.com", Login: "baruser", Password: "barpass"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseNetrc(writeNetrc(t, tt.contents))
+			if err != nil {
+				t.Fatalf("parseNetrc() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("parseNetrc() = %+v, want %+v", entries, tt.want)
+			}
+			for i, want := range tt.want {
+				if entries[i] != want {
+					t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupNetrcMatchesHost(t *testing.T) {
+	t.Setenv("NETRC", writeNetrc(t, "machine example.com\nlogin alice\npassword wonder\n").Name())
+
+	auth, err := lookupNetrc("example.com")
+	if err != nil {
+		t.Fatalf("lookupNetrc() error = %v", err)
+	}
+	if auth == nil || auth.Username != "alice" || auth.Password != "wonder" {
+		t.Fatalf("lookupNetrc() = %+v, want alice/wonder", auth)
+	}
+}
+
+func TestLookupNetrcFallsBackToDefault(t *testing.T) {
+	t.Setenv("NETRC", writeNetrc(t, "machine foo.com\nlogin foouser\npassword foopass\n\ndefault\nlogin defuser\npassword defpass\n").Name())
+
+	auth, err := lookupNetrc("unknown-host.com")
+	if err != nil {
+		t.Fatalf("lookupNetrc() error = %v", err)
+	}
+	if auth == nil || auth.Username != "defuser" || auth.Password != "defpass" {
+		t.Fatalf("lookupNetrc() = %+v, want defuser/defpass", auth)
+	}
+}
+
+func TestLookupNetrcNoMatchNoDefault(t *testing.T) {
+	t.Setenv("NETRC", writeNetrc(t, "machine foo.com\nlogin foouser\npassword foopass\n").Name())
+
+	auth, err := lookupNetrc("unknown-host.com")
+	if err != nil {
+		t.Fatalf("lookupNetrc() error = %v", err)
+	}
+	if auth != nil {
+		t.Fatalf("lookupNetrc() = %+v, want nil", auth)
+	}
+}
+
+func TestLookupNetrcMissingFile(t *testing.T) {
+	t.Setenv("NETRC", filepath.Join(t.TempDir(), "does-not-exist"))
+
+	auth, err := lookupNetrc("example.com")
+	if err != nil {
+		t.Fatalf("lookupNetrc() error = %v", err)
+	}
+	if auth != nil {
+		t.Fatalf("lookupNetrc() = %+v, want nil for a missing .netrc", auth)
+	}
+}
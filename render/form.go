@@ -0,0 +1,35 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// FormRenderer prints an application/x-www-form-urlencoded body as
+// "key: value" lines, one per value, sorted by key for stable output.
+type FormRenderer struct{}
+
+func (FormRenderer) Render(w io.Writer, resp *http.Response, body []byte) error {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return fmt.Errorf("render: invalid form body: %w", err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package render
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+)
+
+// HexDumpRenderer is the fallback for content types render doesn't
+// recognize as text, JSON, XML, or form-encoded - typically binary bodies.
+type HexDumpRenderer struct{}
+
+func (HexDumpRenderer) Render(w io.Writer, resp *http.Response, body []byte) error {
+	dumper := hex.Dumper(w)
+	defer dumper.Close()
+	_, err := dumper.Write(body)
+	return err
+}
@@ -0,0 +1,27 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// JSONRenderer pretty-prints a JSON body. Since it re-indents the raw bytes
+// rather than unmarshaling into a specific Go type, it handles top-level
+// arrays, scalars, and empty bodies as well as objects.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, resp *http.Response, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, body, "", "  "); err != nil {
+		return fmt.Errorf("render: invalid JSON body: %w", err)
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
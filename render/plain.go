@@ -0,0 +1,15 @@
+package render
+
+import (
+	"io"
+	"net/http"
+)
+
+// PlainTextRenderer writes the body through unmodified. It's used for
+// text/* content types and as the -output raw override.
+type PlainTextRenderer struct{}
+
+func (PlainTextRenderer) Render(w io.Writer, resp *http.Response, body []byte) error {
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,60 @@
+// Package render formats an HTTP response body for display, choosing a
+// formatter from the response's Content-Type (or an explicit override).
+package render
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Renderer writes resp's body to w, formatted however that renderer sees
+// fit. body is the already-drained response body (resp.Body has been
+// closed).
+type Renderer interface {
+	Render(w io.Writer, resp *http.Response, body []byte) error
+}
+
+// ForName returns the Renderer named by an explicit -output flag value.
+// "auto" has no single renderer - callers should fall back to
+// ForContentType - so it returns nil, nil.
+func ForName(name string) (Renderer, error) {
+	switch name {
+	case "json":
+		return JSONRenderer{}, nil
+	case "xml":
+		return XMLRenderer{}, nil
+	case "raw":
+		return PlainTextRenderer{}, nil
+	case "auto", "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("render: unknown -output %q", name)
+	}
+}
+
+// ForContentType picks a Renderer based on a Content-Type header value,
+// falling back to a hex dump for anything it doesn't recognize.
+func ForContentType(contentType string) Renderer {
+	mediaType := contentType
+	if i := strings.IndexByte(mediaType, ';'); i != -1 {
+		mediaType = mediaType[:i]
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case mediaType == "":
+		return PlainTextRenderer{}
+	case strings.HasSuffix(mediaType, "/json") || strings.HasSuffix(mediaType, "+json"):
+		return JSONRenderer{}
+	case strings.HasSuffix(mediaType, "/xml") || strings.HasSuffix(mediaType, "+xml"):
+		return XMLRenderer{}
+	case mediaType == "application/x-www-form-urlencoded":
+		return FormRenderer{}
+	case strings.HasPrefix(mediaType, "text/"):
+		return PlainTextRenderer{}
+	default:
+		return HexDumpRenderer{}
+	}
+}
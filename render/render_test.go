@@ -0,0 +1,90 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestForContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Renderer
+	}{
+		{"application/json", JSONRenderer{}},
+		{"application/json; charset=utf-8", JSONRenderer{}},
+		{"application/vnd.api+json", JSONRenderer{}},
+		{"application/xml", XMLRenderer{}},
+		{"text/xml", XMLRenderer{}},
+		{"application/x-www-form-urlencoded", FormRenderer{}},
+		{"text/plain", PlainTextRenderer{}},
+		{"", PlainTextRenderer{}},
+		{"application/octet-stream", HexDumpRenderer{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if got := ForContentType(tt.contentType); got != tt.want {
+				t.Errorf("ForContentType(%q) = %T, want %T", tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForName(t *testing.T) {
+	if r, err := ForName("json"); err != nil || r != (JSONRenderer{}) {
+		t.Errorf("ForName(json) = %v, %v", r, err)
+	}
+	if r, err := ForName("auto"); err != nil || r != nil {
+		t.Errorf("ForName(auto) = %v, %v, want nil, nil", r, err)
+	}
+	if _, err := ForName("yaml"); err == nil {
+		t.Error("ForName(yaml) error = nil, want error for unknown renderer")
+	}
+}
+
+func TestJSONRendererHandlesArrays(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONRenderer{}).Render(&buf, nil, []byte(`[1,2,3]`)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "1") || !strings.Contains(got, "[") {
+		t.Fatalf("Render() output = %q, want indented array", got)
+	}
+}
+
+func TestJSONRendererInvalidBody(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONRenderer{}).Render(&buf, nil, []byte("not json")); err == nil {
+		t.Fatal("Render() error = nil, want error for invalid JSON")
+	}
+}
+
+func TestXMLRendererReindents(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (XMLRenderer{}).Render(&buf, nil, []byte(`<a><b>1</b></a>`)); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "<a>") || !strings.Contains(got, "  <b>1</b>") {
+		t.Fatalf("Render() output = %q, want indented XML", got)
+	}
+}
+
+func TestFormRendererSortsKeys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (FormRenderer{}).Render(&buf, nil, []byte("b=2&a=1")); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got, want := buf.String(), "a: 1\nb: 2\n"; got != want {
+		t.Fatalf("Render() output = %q, want %q", got, want)
+	}
+}
+
+func TestHexDumpRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (HexDumpRenderer{}).Render(&buf, nil, []byte{0x00, 0xff}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := buf.String(); !strings.Contains(got, "00 ff") {
+		t.Fatalf("Render() output = %q, want a hex dump", got)
+	}
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// XMLRenderer re-indents an XML body. It re-encodes the token stream rather
+// than unmarshaling into a struct, so it works for arbitrary, schema-less
+// XML documents.
+type XMLRenderer struct{}
+
+func (XMLRenderer) Render(w io.Writer, resp *http.Response, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("render: invalid XML body: %w", err)
+		}
+		if err := encoder.EncodeToken(tok); err != nil {
+			return fmt.Errorf("render: %w", err)
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte("\n"))
+	return err
+}
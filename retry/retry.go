@@ -0,0 +1,88 @@
+// Package retry implements the backoff policy httpclient uses to re-issue
+// requests that fail transiently - connection errors, DNS failures, and a
+// configurable set of response status codes.
+package retry
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy controls whether and how long to wait before retrying a request.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// a value of 0 or 1 disables retrying.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	StatusCodes map[int]bool
+}
+
+// DefaultStatusCodes are the status codes retried when -retry-on isn't given.
+func DefaultStatusCodes() map[int]bool {
+	return map[int]bool{408: true, 429: true, 500: true, 502: true, 503: true, 504: true}
+}
+
+// ParseStatusCodes parses a comma-separated list like "429,503" into the set
+// ShouldRetry checks against.
+func ParseStatusCodes(s string) (map[int]bool, error) {
+	codes := map[int]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("retry: invalid status code %q", part)
+		}
+		codes[code] = true
+	}
+	return codes, nil
+}
+
+// ShouldRetry reports whether the request should be retried after attempt
+// (0-indexed) came back with statusCode and/or err. A non-nil err (a
+// connection error or DNS failure) is always retried; otherwise statusCode
+// is checked against StatusCodes.
+func (p Policy) ShouldRetry(attempt int, statusCode int, err error) bool {
+	if attempt+1 >= p.MaxAttempts {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return p.StatusCodes[statusCode]
+}
+
+// Delay returns how long to wait before the next attempt. If retryAfter is
+// non-zero (the server sent a Retry-After header), it overrides the
+// computed backoff. Otherwise this is exponential backoff with full
+// jitter: rand(0, min(max, base*2^attempt)).
+func (p Policy) Delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	// Cap the shift so the 2^attempt multiplier stays a positive int64, and
+	// treat a "base*2^attempt" product that wraps around int64 for a large
+	// -retry count as past MaxDelay, where it would have been clamped anyway.
+	const maxShift = 62
+	shift := attempt
+	if shift > maxShift {
+		shift = maxShift
+	}
+	multiplier := time.Duration(int64(1) << uint(shift))
+	backoff := p.BaseDelay * multiplier
+	overflowed := backoff/multiplier != p.BaseDelay
+	if overflowed || backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
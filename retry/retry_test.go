@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	p := Policy{MaxAttempts: 3, StatusCodes: DefaultStatusCodes()}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		statusCode int
+		err        error
+		want       bool
+	}{
+		{"retryable status, attempts left", 0, 503, nil, true},
+		{"non-retryable status", 0, 404, nil, false},
+		{"connection error always retried", 0, 0, errors.New("dial tcp: timeout"), true},
+		{"out of attempts", 2, 503, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.ShouldRetry(tt.attempt, tt.statusCode, tt.err); got != tt.want {
+				t.Errorf("ShouldRetry(%d, %d, %v) = %v, want %v", tt.attempt, tt.statusCode, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	codes, err := ParseStatusCodes("429, 503")
+	if err != nil {
+		t.Fatalf("ParseStatusCodes() error = %v", err)
+	}
+	if !codes[429] || !codes[503] || len(codes) != 2 {
+		t.Fatalf("ParseStatusCodes() = %v, want {429,503}", codes)
+	}
+
+	if _, err := ParseStatusCodes("429,nope"); err == nil {
+		t.Fatal("ParseStatusCodes() error = nil, want error for non-numeric code")
+	}
+}
+
+func TestDelayHonorsRetryAfter(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	if got := p.Delay(0, 5*time.Second); got != 5*time.Second {
+		t.Fatalf("Delay() = %v, want 5s (Retry-After override)", got)
+	}
+}
+
+func TestDelayCapsAtMax(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		if got := p.Delay(attempt, 0); got > p.MaxDelay {
+			t.Fatalf("Delay(%d, 0) = %v, want <= %v", attempt, got, p.MaxDelay)
+		}
+	}
+}
+
+func TestDelayCapsAtMaxForLargeAttempt(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	// A large enough attempt overflows the naive base*2^attempt shift; it
+	// must still clamp to MaxDelay rather than wrapping around to 0.
+	if got := p.Delay(100, 0); got > p.MaxDelay || got <= 0 {
+		t.Fatalf("Delay(100, 0) = %v, want a positive value <= %v", got, p.MaxDelay)
+	}
+}
+
+func TestRetryAfterDeltaSeconds(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	d, ok := RetryAfter(resp)
+	if !ok || d != 120*time.Second {
+		t.Fatalf("RetryAfter() = %v, %v, want 120s, true", d, ok)
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(time.Minute)
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{future.UTC().Format(http.TimeFormat)}}}
+	d, ok := RetryAfter(resp)
+	if !ok || d <= 0 || d > time.Minute {
+		t.Fatalf("RetryAfter() = %v, %v, want a positive duration near 1m", d, ok)
+	}
+}
+
+func TestRetryAfterMissing(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	if _, ok := RetryAfter(resp); ok {
+		t.Fatal("RetryAfter() ok = true, want false when header absent")
+	}
+}
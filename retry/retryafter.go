@@ -0,0 +1,34 @@
+package retry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryAfter parses resp's Retry-After header, supporting both the
+// delta-seconds form ("120") and the HTTP-date form
+// ("Fri, 31 Dec 1999 23:59:59 GMT"). It returns 0, false if resp has no such
+// header, or it can't be parsed.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	when, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(when); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
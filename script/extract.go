@@ -0,0 +1,78 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Extract pulls a value out of a step's response per expr:
+//
+//   - "header:Name" looks up a response header.
+//   - anything else is a dotted path into the JSON body, e.g. "$.data.token"
+//     or "data.token" (a leading "$." is optional); array elements are
+//     indexed numerically, e.g. "data.items.0.id".
+func Extract(expr string, header http.Header, body []byte) (string, error) {
+	if name, ok := strings.CutPrefix(expr, "header:"); ok {
+		if v := header.Get(name); v != "" {
+			return v, nil
+		}
+		return "", fmt.Errorf("header %q not present in response", name)
+	}
+	return extractJSONPath(expr, body)
+}
+
+func extractJSONPath(expr string, body []byte) (string, error) {
+	path := strings.TrimPrefix(expr, "$.")
+	path = strings.TrimPrefix(path, "$")
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("extracting %q: response body is not JSON: %w", expr, err)
+	}
+
+	cur := data
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			next, err := index(cur, segment)
+			if err != nil {
+				return "", fmt.Errorf("extracting %q: %w", expr, err)
+			}
+			cur = next
+		}
+	}
+
+	switch v := cur.(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", fmt.Errorf("extracting %q: value is null", expr)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("extracting %q: %w", expr, err)
+		}
+		return string(encoded), nil
+	}
+}
+
+func index(v interface{}, segment string) (interface{}, error) {
+	switch container := v.(type) {
+	case map[string]interface{}:
+		val, ok := container[segment]
+		if !ok {
+			return nil, fmt.Errorf("no field %q", segment)
+		}
+		return val, nil
+	case []interface{}:
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= len(container) {
+			return nil, fmt.Errorf("invalid array index %q", segment)
+		}
+		return container[i], nil
+	default:
+		return nil, fmt.Errorf("cannot index %T with %q", v, segment)
+	}
+}
@@ -0,0 +1,47 @@
+package script
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	body := []byte(`{"data":{"token":"abc123","items":[{"id":1},{"id":2}]}}`)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"$.data.token", "abc123"},
+		{"data.token", "abc123"},
+		{"data.items.1.id", "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Extract(tt.expr, http.Header{}, body)
+			if err != nil {
+				t.Fatalf("Extract(%q) error = %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Extract(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHeader(t *testing.T) {
+	header := http.Header{"Location": []string{"https://example.com/result"}}
+	got, err := Extract("header:Location", header, nil)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got != "https://example.com/result" {
+		t.Fatalf("Extract() = %q", got)
+	}
+}
+
+func TestExtractMissingField(t *testing.T) {
+	if _, err := Extract("$.data.missing", http.Header{}, []byte(`{"data":{}}`)); err == nil {
+		t.Fatal("Extract() error = nil, want error for missing field")
+	}
+}
@@ -0,0 +1,104 @@
+package script
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gitynity/httppal/auth"
+	"github.com/gitynity/httppal/httpclient"
+)
+
+// AuthResolver picks the auth.Provider to use for a step's substituted URL,
+// returning the URL to actually request (with any userinfo credentials it
+// consumed stripped out).
+type AuthResolver func(rawURL string) (auth.Provider, string, error)
+
+// StepResult pairs a Step with the response it produced.
+type StepResult struct {
+	Step     Step
+	Response *httpclient.Response
+}
+
+// Runner executes a script's steps in order against a shared Client,
+// substituting {{var}} placeholders and feeding each step's extracted
+// values into Vars before moving on to the next step.
+type Runner struct {
+	Client *httpclient.Client
+	Vars   Vars
+
+	// Defaults is applied to every step's request (retry policy, redirect
+	// handling); each step only supplies its method, URL, headers, and
+	// body.
+	Defaults httpclient.RequestParams
+
+	// AuthResolver, if set, is called with each step's substituted URL to
+	// pick that step's auth.Provider (e.g. from -auth, the URL's userinfo,
+	// or .netrc) and returns the URL to actually request (with any
+	// userinfo credentials stripped). Steps can target different hosts,
+	// so this runs per step rather than being fixed in Defaults.Auth.
+	AuthResolver AuthResolver
+}
+
+// NewRunner returns a Runner seeded with vars (which may be nil).
+func NewRunner(client *httpclient.Client, vars Vars, defaults httpclient.RequestParams) *Runner {
+	if vars == nil {
+		vars = Vars{}
+	}
+	return &Runner{Client: client, Vars: vars, Defaults: defaults}
+}
+
+// Run executes steps in order. callback, if non-nil, is invoked with each
+// step's result as it completes, so callers can stream output rather than
+// waiting for the whole script to finish.
+func (r *Runner) Run(ctx context.Context, steps []Step, callback func(StepResult)) error {
+	for _, step := range steps {
+		params := r.Defaults
+		params.Method = step.Method
+		params.URL = r.Vars.Substitute(step.URL)
+		params.Headers = substituteHeaders(step.Headers, r.Vars)
+		if step.Body != "" {
+			params.Body = []byte(r.Vars.Substitute(step.Body))
+		} else {
+			params.Body = nil
+		}
+
+		if r.AuthResolver != nil {
+			provider, url, err := r.AuthResolver(params.URL)
+			if err != nil {
+				return fmt.Errorf("script: step %q: %w", step.Name, err)
+			}
+			params.URL = url
+			params.Auth = provider
+		}
+
+		resp, err := r.Client.Do(ctx, params)
+		if err != nil {
+			return fmt.Errorf("script: step %q: %w", step.Name, err)
+		}
+
+		for name, expr := range step.Extract {
+			value, err := Extract(expr, resp.Header, resp.Body)
+			if err != nil {
+				return fmt.Errorf("script: step %q: %w", step.Name, err)
+			}
+			r.Vars[name] = value
+		}
+
+		if callback != nil {
+			callback(StepResult{Step: step, Response: resp})
+		}
+	}
+	return nil
+}
+
+func substituteHeaders(headers map[string][]string, vars Vars) map[string][]string {
+	out := make(map[string][]string, len(headers))
+	for k, values := range headers {
+		substituted := make([]string, len(values))
+		for i, v := range values {
+			substituted[i] = vars.Substitute(v)
+		}
+		out[k] = substituted
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package script
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gitynity/httppal/auth"
+	"github.com/gitynity/httppal/httpclient"
+)
+
+func TestRunnerExtractsAndSubstitutesBetweenSteps(t *testing.T) {
+	var gotAuthHeader string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"data":{"token":"abc123"}}`))
+		case "/data":
+			gotAuthHeader = r.Header.Get("Authorization")
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	steps := []Step{
+		{
+			Name:    "login",
+			Method:  http.MethodGet,
+			URL:     srv.URL + "/login",
+			Headers: http.Header{},
+			Extract: map[string]string{"token": "$.data.token"},
+		},
+		{
+			Name:    "fetch",
+			Method:  http.MethodGet,
+			URL:     srv.URL + "/data",
+			Headers: http.Header{"Authorization": {"Bearer {{token}}"}},
+			Extract: map[string]string{},
+		},
+	}
+
+	runner := NewRunner(httpclient.New(), nil, httpclient.RequestParams{})
+	var results []StepResult
+	err := runner.Run(context.Background(), steps, func(r StepResult) {
+		results = append(results, r)
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	if gotAuthHeader != "Bearer abc123" {
+		t.Fatalf("Authorization sent to /data = %q, want %q", gotAuthHeader, "Bearer abc123")
+	}
+	if runner.Vars["token"] != "abc123" {
+		t.Fatalf("Vars[token] = %q, want abc123", runner.Vars["token"])
+	}
+}
+
+func TestRunnerAppliesAuthResolverPerStep(t *testing.T) {
+	var gotAuthHeader string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthHeader = r.Header.Get("Authorization")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	provider, err := auth.NewProvider(auth.Params{Type: auth.Basic, Username: "alice", Password: "wonder"})
+	if err != nil {
+		t.Fatalf("auth.NewProvider() error = %v", err)
+	}
+
+	runner := NewRunner(httpclient.New(), nil, httpclient.RequestParams{})
+	runner.AuthResolver = func(rawURL string) (auth.Provider, string, error) {
+		return provider, rawURL, nil
+	}
+
+	steps := []Step{
+		{Name: "get", Method: http.MethodGet, URL: srv.URL, Headers: http.Header{}, Extract: map[string]string{}},
+	}
+	if err := runner.Run(context.Background(), steps, nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if gotAuthHeader == "" || !strings.HasPrefix(gotAuthHeader, "Basic ") {
+		t.Fatalf("Authorization sent = %q, want a Basic auth header", gotAuthHeader)
+	}
+}
@@ -0,0 +1,132 @@
+// Package script implements httppal's -script mode: a sequence of requests,
+// read from a JetBrains/VS Code REST Client-style ".http" file, where each
+// step can extract a value from its response into a named variable for
+// substitution into later steps via {{var}}.
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Step is one request parsed from a "### name" delimited block.
+type Step struct {
+	Name    string
+	Method  string
+	URL     string
+	Headers http.Header
+	Body    string
+	// Extract maps a variable name to the expression used to pull its
+	// value out of this step's response (see Extract in extract.go).
+	Extract map[string]string
+}
+
+// Parse reads a script file. Steps are separated by a line starting with
+// "###", optionally followed by the step's name. The first non-blank line
+// of a step is "METHOD URL"; subsequent "Header: value" lines are request
+// headers until a blank line, after which everything up to the next "###"
+// is the request body. A "# @extract name = expr" line, in the header block
+// or the body, records a value to capture from the response for later steps
+// and is not itself part of the body.
+func Parse(r io.Reader) ([]Step, error) {
+	var steps []Step
+	var cur *Step
+	var body strings.Builder
+	inBody := false
+
+	flushBody := func() {
+		if cur != nil {
+			cur.Body = strings.TrimRight(body.String(), "\n")
+		}
+		body.Reset()
+		inBody = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "###") {
+			flushBody()
+			steps = append(steps, Step{
+				Name:    strings.TrimSpace(strings.TrimPrefix(line, "###")),
+				Headers: http.Header{},
+				Extract: map[string]string{},
+			})
+			cur = &steps[len(steps)-1]
+			continue
+		}
+		if cur == nil {
+			continue // ignore anything before the first ### delimiter
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		// "# @extract" directives are recognized in the body as well as
+		// the header block, so a step can extract from its response after
+		// its request body is written - the common login-then-call layout.
+		if strings.HasPrefix(trimmed, "# @extract ") {
+			name, expr, err := parseExtractDirective(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("script: line %d: %w", lineNo, err)
+			}
+			cur.Extract[name] = expr
+			continue
+		}
+
+		if inBody {
+			body.WriteString(line)
+			body.WriteByte('\n')
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			// blank lines before the request line are skipped
+			if cur.Method != "" {
+				inBody = true
+			}
+		case strings.HasPrefix(trimmed, "#"):
+			// a plain comment
+		case cur.Method == "":
+			method, url, err := parseRequestLine(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("script: line %d: %w", lineNo, err)
+			}
+			cur.Method, cur.URL = method, url
+		default:
+			key, value, ok := strings.Cut(trimmed, ":")
+			if !ok {
+				return nil, fmt.Errorf("script: line %d: invalid header %q", lineNo, trimmed)
+			}
+			cur.Headers.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+		}
+	}
+	flushBody()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+func parseRequestLine(line string) (method, url string, err error) {
+	parts := strings.Fields(line)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("expected \"METHOD URL\", got %q", line)
+	}
+	return strings.ToUpper(parts[0]), parts[1], nil
+}
+
+func parseExtractDirective(line string) (name, expr string, err error) {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "# @extract "))
+	name, expr, ok := strings.Cut(rest, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid @extract directive %q (want \"name = expr\")", line)
+	}
+	return strings.TrimSpace(name), strings.TrimSpace(expr), nil
+}
@@ -0,0 +1,54 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleScript = `### login
+POST https://api.example.com/login
+Content-Type: application/json
+
+{"user":"alice","password":"wonder"}
+
+# @extract token = $.data.token
+
+### fetch
+GET https://api.example.com/data
+Authorization: Bearer {{token}}
+`
+
+func TestParse(t *testing.T) {
+	steps, err := Parse(strings.NewReader(sampleScript))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("len(steps) = %d, want 2", len(steps))
+	}
+
+	login := steps[0]
+	if login.Name != "login" || login.Method != "POST" || login.URL != "https://api.example.com/login" {
+		t.Fatalf("login step = %+v", login)
+	}
+	if got := login.Headers.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", got)
+	}
+	if got := strings.TrimSpace(login.Body); got != `{"user":"alice","password":"wonder"}` {
+		t.Fatalf("Body = %q", got)
+	}
+	if login.Extract["token"] != "$.data.token" {
+		t.Fatalf("Extract[token] = %q, want $.data.token", login.Extract["token"])
+	}
+
+	fetch := steps[1]
+	if fetch.Headers.Get("Authorization") != "Bearer {{token}}" {
+		t.Fatalf("Authorization = %q", fetch.Headers.Get("Authorization"))
+	}
+}
+
+func TestParseInvalidRequestLine(t *testing.T) {
+	if _, err := Parse(strings.NewReader("### bad\nNOT-A-REQUEST-LINE\n")); err == nil {
+		t.Fatal("Parse() error = nil, want error for malformed request line")
+	}
+}
@@ -0,0 +1,65 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Vars holds named string values substituted into {{name}} placeholders in
+// a script's URLs, headers, and bodies.
+type Vars map[string]string
+
+var placeholderRE = regexp.MustCompile(`\{\{\s*([\w.-]+)\s*\}\}`)
+
+// Substitute replaces every {{name}} placeholder in s with vars[name],
+// leaving unknown placeholders untouched so a missing variable is obvious
+// in the request that goes out rather than silently becoming "".
+func (v Vars) Substitute(s string) string {
+	return placeholderRE.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholderRE.FindStringSubmatch(match)[1]
+		if val, ok := v[name]; ok {
+			return val
+		}
+		return match
+	})
+}
+
+// ParseVarFlag parses a single "-var key=value" flag value.
+func ParseVarFlag(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid -var %q (want key=value)", s)
+	}
+	return key, value, nil
+}
+
+// LoadEnvFile reads a "-env" file of key=value lines, one per line, with
+// blank lines and lines starting with "#" ignored.
+func LoadEnvFile(path string) (Vars, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("script: reading env file: %w", err)
+	}
+	defer f.Close()
+
+	vars := Vars{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, err := ParseVarFlag(line)
+		if err != nil {
+			return nil, fmt.Errorf("script: %s: %w", path, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
@@ -0,0 +1,34 @@
+package script
+
+import "testing"
+
+func TestSubstitute(t *testing.T) {
+	vars := Vars{"token": "abc123", "host": "example.com"}
+	got := vars.Substitute("https://{{host}}/data?token={{token}}")
+	want := "https://example.com/data?token=abc123"
+	if got != want {
+		t.Fatalf("Substitute() = %q, want %q", got, want)
+	}
+}
+
+func TestSubstituteLeavesUnknownPlaceholders(t *testing.T) {
+	vars := Vars{}
+	got := vars.Substitute("{{missing}}")
+	if got != "{{missing}}" {
+		t.Fatalf("Substitute() = %q, want placeholder left untouched", got)
+	}
+}
+
+func TestParseVarFlag(t *testing.T) {
+	key, value, err := ParseVarFlag("token=abc123")
+	if err != nil {
+		t.Fatalf("ParseVarFlag() error = %v", err)
+	}
+	if key != "token" || value != "abc123" {
+		t.Fatalf("ParseVarFlag() = %q, %q", key, value)
+	}
+
+	if _, _, err := ParseVarFlag("no-equals-sign"); err == nil {
+		t.Fatal("ParseVarFlag() error = nil, want error")
+	}
+}